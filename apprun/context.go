@@ -0,0 +1,24 @@
+package apprun
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+type loggerCtxKey struct{}
+
+// WithLogger returns a copy of ctx carrying l, retrievable with LoggerFromContext.
+func WithLogger(ctx context.Context, l zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// LoggerFromContext returns the logger attached with WithLogger, or the
+// zerolog no-op logger if ctx carries none.
+func LoggerFromContext(ctx context.Context) zerolog.Logger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(zerolog.Logger); ok {
+		return l
+	}
+
+	return zerolog.Nop()
+}
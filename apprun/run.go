@@ -85,6 +85,7 @@ func Run[AT app, CT any](f factory[AT, CT], appCfg CT, appName, appVer string, l
 
 	ctx, ctxC := context.WithCancel(context.Background())
 	defer ctxC()
+	ctx = WithLogger(ctx, bl)
 
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
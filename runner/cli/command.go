@@ -0,0 +1,26 @@
+// Package cli provides a minimal command tree for apps built on runner.Runner,
+// wired in via Runner.WithCLI.
+package cli
+
+import "context"
+
+// Command is a single CLI subcommand, composable into a tree via Subcommands.
+// A Command with no Action is a grouping node, e.g. "config" for "config
+// print" and "config validate".
+type Command struct {
+	Name        string
+	Usage       string
+	Action      func(ctx context.Context, args []string) error
+	Subcommands []*Command
+}
+
+// Find returns the subcommand named name, or nil if there is none.
+func (c *Command) Find(name string) *Command {
+	for _, sc := range c.Subcommands {
+		if sc.Name == name {
+			return sc
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ashep/go-apprun/runner/cli"
+)
+
+// WithCLI attaches a command tree to the runner. Running the binary with no
+// arguments (or "run") starts the app as usual; any other first argument is
+// looked up in root's subcommand tree. Built-in "config print", "config
+// validate", "version", and "health" subcommands are added to root
+// automatically unless root already defines commands with those names.
+func (r *Runner[RT, CT]) WithCLI(root *cli.Command) *Runner[RT, CT] {
+	for _, c := range r.builtinCLICommands() {
+		if root.Find(c.Name) == nil {
+			root.Subcommands = append(root.Subcommands, c)
+		}
+	}
+
+	r.cli = root
+
+	return r
+}
+
+func (r *Runner[RT, CT]) builtinCLICommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "config",
+			Usage: "inspect the effective app config",
+			Subcommands: []*cli.Command{
+				{Name: "print", Usage: "print the effective merged config as JSON", Action: r.cliConfigPrint},
+				{Name: "validate", Usage: "load and validate the effective config without starting the app", Action: r.cliConfigValidate},
+			},
+		},
+		{Name: "version", Usage: "print app name, version, and build info", Action: r.cliVersion},
+		{Name: "health", Usage: "call the local /healthz endpoint", Action: r.cliHealth},
+	}
+}
+
+func (r *Runner[RT, CT]) runCLI(args []string) int {
+	if len(args) == 0 || args[0] == "run" {
+		return r.run()
+	}
+
+	cmd := r.cli.Find(args[0])
+	if cmd == nil {
+		r.rt.Logger.Error().Str("command", args[0]).Msg("unknown command")
+		return 1
+	}
+
+	name, rest := args[0], args[1:]
+
+	if cmd.Action == nil {
+		if len(rest) == 0 {
+			r.rt.Logger.Error().Str("command", name).Msg("missing subcommand")
+			return 1
+		}
+
+		sub := cmd.Find(rest[0])
+		if sub == nil || sub.Action == nil {
+			r.rt.Logger.Error().Str("command", name+" "+rest[0]).Msg("unknown command")
+			return 1
+		}
+
+		name, cmd, rest = name+" "+rest[0], sub, rest[1:]
+	}
+
+	if err := cmd.Action(context.Background(), rest); err != nil {
+		r.rt.Logger.Error().Err(err).Str("command", name).Msg("command failed")
+		return 1
+	}
+
+	return 0
+}
+
+func (r *Runner[RT, CT]) cliConfigPrint(ctx context.Context, _ []string) error {
+	if err := r.loadConfig(ctx); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(r.cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+
+	fmt.Println(string(b))
+
+	return nil
+}
+
+// configValidator is implemented by app config types that want "config
+// validate" (and config reload) to reject values that loaded cleanly but
+// don't make sense together, e.g. a port out of range or a required field
+// left empty.
+type configValidator interface {
+	Validate() error
+}
+
+func (r *Runner[RT, CT]) cliConfigValidate(ctx context.Context, _ []string) error {
+	if err := r.loadConfig(ctx); err != nil {
+		return err
+	}
+
+	if v, ok := any(r.cfg).(configValidator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("config is invalid: %w", err)
+		}
+	}
+
+	fmt.Println("config OK")
+
+	return nil
+}
+
+func (r *Runner[RT, CT]) cliVersion(_ context.Context, _ []string) error {
+	fmt.Printf("%s %s\n", r.rt.AppName, r.rt.AppVersion)
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	fmt.Printf("go: %s\n", bi.GoVersion)
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			fmt.Printf("%s: %s\n", s.Key, s.Value)
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner[RT, CT]) cliHealth(ctx context.Context, _ []string) error {
+	if r.srv == nil {
+		return errors.New("http server is not configured")
+	}
+
+	addr := r.srv.Addr
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/healthz", nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("call /healthz: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	fmt.Println(string(body))
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unhealthy: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ashep/go-cfgloader"
+)
+
+// ConfigSource is a pluggable origin for config values, layered between the
+// built-in file loader and environment variables (see WithConfigSource).
+// Watch lets an app receive updates without a restart; a source that
+// doesn't support hot reload should return a nil channel and a nil error.
+type ConfigSource interface {
+	Load(ctx context.Context, into any) error
+	Watch(ctx context.Context) (<-chan struct{}, error)
+}
+
+// fileConfigSource is the default source: "config.yaml"/"config.json",
+// "<appName>.yaml"/"<appName>.json", and, if set, APP_CONFIG_PATH.
+type fileConfigSource struct {
+	appName string
+}
+
+func (s fileConfigSource) Load(_ context.Context, into any) error {
+	for _, base := range []string{"config", s.appName} {
+		for _, ext := range []string{".yaml", ".json"} {
+			cfgPath := base + ext
+
+			err := cfgloader.LoadFromPath(cfgPath, into, nil)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("load config from %q: %w", cfgPath, err)
+			}
+		}
+	}
+
+	if cfgPath := os.Getenv("APP_CONFIG_PATH"); cfgPath != "" {
+		if err := cfgloader.LoadFromPath(cfgPath, into, nil); err != nil {
+			return fmt.Errorf("load config from %q: %w", cfgPath, err)
+		}
+	}
+
+	return nil
+}
+
+func (fileConfigSource) Watch(context.Context) (<-chan struct{}, error) {
+	return nil, nil //nolint:nilnil // no hot-reload support for local files
+}
+
+// envConfigSource is the highest-precedence default source: env vars
+// prefixed APP_, applied after files and custom sources and before explicit
+// overrides.
+type envConfigSource struct{}
+
+func (envConfigSource) Load(_ context.Context, into any) error {
+	if err := cfgloader.LoadFromEnv("APP", into); err != nil {
+		return fmt.Errorf("load config from env vars: %w", err)
+	}
+
+	return nil
+}
+
+func (envConfigSource) Watch(context.Context) (<-chan struct{}, error) {
+	return nil, nil //nolint:nilnil // env vars don't change under a running process
+}
+
+// overrideConfigSource is the highest-precedence source: explicit values set
+// by app code via Runner.WithConfigOverrides, applied after everything else.
+type overrideConfigSource[CT any] struct {
+	fn func(*CT)
+}
+
+func (s overrideConfigSource[CT]) Load(_ context.Context, into any) error {
+	cfg, ok := into.(*CT)
+	if !ok {
+		return fmt.Errorf("override source: unexpected config type %T", into)
+	}
+
+	s.fn(cfg)
+
+	return nil
+}
+
+func (overrideConfigSource[CT]) Watch(context.Context) (<-chan struct{}, error) {
+	return nil, nil //nolint:nilnil // overrides are static, set once at startup
+}
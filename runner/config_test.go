@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type testConfig struct {
+	Value string
+}
+
+type fakeApp struct{}
+
+func (fakeApp) Run(context.Context) error { return nil }
+
+func newTestRunner(t *testing.T) *Runner[*fakeApp, testConfig] {
+	t.Helper()
+
+	return New[*fakeApp, testConfig](func(testConfig, *Runtime[testConfig]) (*fakeApp, error) {
+		return &fakeApp{}, nil
+	}, testConfig{})
+}
+
+type fakeConfigSource struct {
+	value string
+}
+
+func (f fakeConfigSource) Load(_ context.Context, into any) error {
+	into.(*testConfig).Value = f.value //nolint:forcetypeassert // test double, type is always testConfig
+	return nil
+}
+
+func (fakeConfigSource) Watch(context.Context) (<-chan struct{}, error) {
+	return nil, nil //nolint:nilnil // test double
+}
+
+func TestConfigSourceChainPrecedence(t *testing.T) {
+	r := newTestRunner(t)
+	r.WithConfigSource(fakeConfigSource{value: "first-custom"})
+	r.WithConfigSource(fakeConfigSource{value: "second-custom"})
+
+	if err := r.loadConfig(context.Background()); err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	if r.cfg.Value != "second-custom" {
+		t.Fatalf("want the later-registered custom source to win, got %q", r.cfg.Value)
+	}
+
+	r.WithConfigOverrides(func(c *testConfig) { c.Value = "override" })
+
+	if err := r.loadConfig(context.Background()); err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	if r.cfg.Value != "override" {
+		t.Fatalf("want explicit overrides to win over every other source, got %q", r.cfg.Value)
+	}
+}
+
+// TestReloadConfigConcurrentSourcesRace is a regression test for reloadConfig
+// racing on r.cfg when multiple watched config sources fire at once; run with
+// -race.
+func TestReloadConfigConcurrentSourcesRace(t *testing.T) {
+	r := newTestRunner(t)
+	r.WithConfigSource(fakeConfigSource{value: "first-custom"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.reloadConfig(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if r.cfg.Value != "first-custom" {
+		t.Fatalf("want reloaded value %q, got %q", "first-custom", r.cfg.Value)
+	}
+}
+
+type levelConfig struct {
+	Level zerolog.Level
+}
+
+func (c levelConfig) LogLevel() zerolog.Level { return c.Level }
+
+type levelConfigSource struct {
+	level zerolog.Level
+}
+
+func (s levelConfigSource) Load(_ context.Context, into any) error {
+	into.(*levelConfig).Level = s.level //nolint:forcetypeassert // test double
+	return nil
+}
+
+func (levelConfigSource) Watch(context.Context) (<-chan struct{}, error) {
+	return nil, nil //nolint:nilnil // test double
+}
+
+// TestReloadConfigAppliesLogLevelFromConfig is a regression test for
+// reloadConfig deriving the log level from the reloaded config (via
+// configLogLeveler) instead of re-reading APP_DEBUG, which can't change
+// under a running process.
+func TestReloadConfigAppliesLogLevelFromConfig(t *testing.T) {
+	defer zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+	r := New[*fakeApp, levelConfig](func(levelConfig, *Runtime[levelConfig]) (*fakeApp, error) {
+		return &fakeApp{}, nil
+	}, levelConfig{Level: zerolog.InfoLevel})
+	r.WithConfigSource(levelConfigSource{level: zerolog.DebugLevel})
+
+	r.reloadConfig(context.Background())
+
+	if got := zerolog.GlobalLevel(); got != zerolog.DebugLevel {
+		t.Fatalf("want global log level debug after reload, got %v", got)
+	}
+}
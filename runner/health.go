@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthCheckKind tells the health-check subsystem whether a check contributes
+// to liveness (is the process alive at all) or readiness (can it currently
+// accept traffic).
+type HealthCheckKind int
+
+const (
+	HealthCheckLiveness HealthCheckKind = iota
+	HealthCheckReadiness
+)
+
+func (k HealthCheckKind) String() string {
+	switch k {
+	case HealthCheckLiveness:
+		return "liveness"
+	case HealthCheckReadiness:
+		return "readiness"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthCheckFunc is a single probe contributed by app code or a library.
+// It should return quickly and return a non-nil error if the check failed.
+type HealthCheckFunc func(ctx context.Context) error
+
+type healthCheck struct {
+	name string
+	kind HealthCheckKind
+	fn   HealthCheckFunc
+}
+
+type healthCheckResult struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type healthResponse struct {
+	Status string              `json:"status"`
+	Checks []healthCheckResult `json:"checks"`
+}
+
+// RegisterHealthCheck registers a named probe of the given kind. It is safe
+// to call concurrently and is typically done from app or library init code.
+func (rt *Runtime[CT]) RegisterHealthCheck(name string, kind HealthCheckKind, fn HealthCheckFunc) {
+	rt.state.healthMu.Lock()
+	defer rt.state.healthMu.Unlock()
+
+	rt.state.healthChecks = append(rt.state.healthChecks, healthCheck{name: name, kind: kind, fn: fn})
+}
+
+// SetReady flips the readiness state reported by /readyz. The
+// runner calls this with false at the start of shutdown so load balancers
+// can stop sending new requests before the app context is cancelled.
+func (rt *Runtime[CT]) SetReady(ready bool) {
+	rt.state.ready.Store(ready)
+}
+
+func (rt *Runtime[CT]) runHealthChecks(ctx context.Context, kind *HealthCheckKind) healthResponse {
+	rt.state.healthMu.Lock()
+	checks := make([]healthCheck, len(rt.state.healthChecks))
+	copy(checks, rt.state.healthChecks)
+	rt.state.healthMu.Unlock()
+
+	resp := healthResponse{Status: "ok"}
+
+	for _, c := range checks {
+		if kind != nil && c.kind != *kind {
+			continue
+		}
+
+		start := time.Now()
+		err := c.fn(ctx)
+		res := healthCheckResult{
+			Name:      c.name,
+			Kind:      c.kind.String(),
+			Status:    "ok",
+			LatencyMS: time.Since(start).Milliseconds(),
+		}
+
+		if err != nil {
+			res.Status = "error"
+			res.Error = err.Error()
+			resp.Status = "error"
+		}
+
+		resp.Checks = append(resp.Checks, res)
+	}
+
+	return resp
+}
+
+func writeHealthResponse(w http.ResponseWriter, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if resp.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// WithHealthChecks registers /healthz, /readyz, and /livez on the runtime's
+// SrvMux. /healthz runs every registered check, /readyz runs only readiness
+// checks (and fails immediately once SetReady(false) was called), and
+// /livez runs only liveness checks.
+func (r *Runner[RT, CT]) WithHealthChecks() *Runner[RT, CT] {
+	if r.srv == nil {
+		panic("http server is not set")
+	}
+
+	r.rt.SrvMux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		writeHealthResponse(w, r.rt.runHealthChecks(req.Context(), nil))
+	})
+
+	r.rt.SrvMux.HandleFunc("/readyz", func(w http.ResponseWriter, req *http.Request) {
+		if !r.rt.state.ready.Load() {
+			writeHealthResponse(w, healthResponse{Status: "not_ready"})
+			return
+		}
+
+		kind := HealthCheckReadiness
+		writeHealthResponse(w, r.rt.runHealthChecks(req.Context(), &kind))
+	})
+
+	r.rt.SrvMux.HandleFunc("/livez", func(w http.ResponseWriter, req *http.Request) {
+		kind := HealthCheckLiveness
+		writeHealthResponse(w, r.rt.runHealthChecks(req.Context(), &kind))
+	})
+
+	return r
+}
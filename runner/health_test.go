@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunHealthChecksKindFiltering(t *testing.T) {
+	rt := &Runtime[any]{state: &runtimeState{}}
+
+	rt.RegisterHealthCheck("live", HealthCheckLiveness, func(context.Context) error { return nil })
+	rt.RegisterHealthCheck("ready-ok", HealthCheckReadiness, func(context.Context) error { return nil })
+	rt.RegisterHealthCheck("ready-bad", HealthCheckReadiness, func(context.Context) error { return errors.New("db down") })
+
+	resp := rt.runHealthChecks(context.Background(), nil)
+	if len(resp.Checks) != 3 {
+		t.Fatalf("want 3 checks with no kind filter, got %d", len(resp.Checks))
+	}
+	if resp.Status != "error" {
+		t.Fatalf("want overall status error when a check fails, got %q", resp.Status)
+	}
+
+	liveness := HealthCheckLiveness
+	resp = rt.runHealthChecks(context.Background(), &liveness)
+	if len(resp.Checks) != 1 || resp.Checks[0].Name != "live" {
+		t.Fatalf("want only the liveness check, got %+v", resp.Checks)
+	}
+	if resp.Status != "ok" {
+		t.Fatalf("want status ok when the only matching check passes, got %q", resp.Status)
+	}
+
+	readiness := HealthCheckReadiness
+	resp = rt.runHealthChecks(context.Background(), &readiness)
+	if len(resp.Checks) != 2 {
+		t.Fatalf("want 2 readiness checks, got %d", len(resp.Checks))
+	}
+	if resp.Status != "error" {
+		t.Fatalf("want status error since one readiness check fails, got %q", resp.Status)
+	}
+}
@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ashep/go-apprun/apprun"
+	"github.com/rs/zerolog"
+)
+
+// LoggerFromContext returns the logger attached by LogMiddleware or injected
+// into the app's Run context, or the zerolog no-op logger if none was
+// attached. It's an alias for apprun.LoggerFromContext so the whole module
+// agrees on a single context key.
+func LoggerFromContext(ctx context.Context) zerolog.Logger {
+	return apprun.LoggerFromContext(ctx)
+}
+
+// LogMiddleware attaches a per-request logger carrying request_id, method,
+// path, and remote_addr to the request context, so handlers registered on
+// SrvMux can pull a consistently-tagged logger via LoggerFromContext.
+func (rt *Runtime[CT]) LogMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			l := rt.Logger.With().
+				Str("request_id", req.Header.Get("X-Request-Id")).
+				Str("method", req.Method).
+				Str("path", req.URL.Path).
+				Str("remote_addr", req.RemoteAddr).
+				Logger()
+
+			ctx := apprun.WithLogger(req.Context(), l)
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// OTelOption customizes WithOpenTelemetry.
+type OTelOption func(*otelOptions)
+
+type otelOptions struct {
+	serviceName string
+	attrs       []attribute.KeyValue
+}
+
+// WithOTelServiceName overrides the resource's service.name attribute,
+// which otherwise defaults to the runner's app name.
+func WithOTelServiceName(name string) OTelOption {
+	return func(o *otelOptions) { o.serviceName = name }
+}
+
+// WithOTelAttributes adds extra resource attributes, e.g. deployment.environment.
+func WithOTelAttributes(attrs ...attribute.KeyValue) OTelOption {
+	return func(o *otelOptions) { o.attrs = append(o.attrs, attrs...) }
+}
+
+// WithOpenTelemetry initializes an OTel TracerProvider and MeterProvider,
+// exporting via OTLP per the standard OTEL_EXPORTER_OTLP_* env vars, and
+// makes them available as Runtime.Tracer and Runtime.Meter. If an HTTP
+// server is set, incoming requests on SrvMux get spans and RED metrics
+// automatically. Both providers are flushed and closed as an OnShutdown
+// hook, so teardown happens in step with the rest of the phased shutdown.
+func (r *Runner[RT, CT]) WithOpenTelemetry(opts ...OTelOption) *Runner[RT, CT] {
+	o := otelOptions{serviceName: r.rt.AppName}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx := context.Background()
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			append([]attribute.KeyValue{
+				semconv.ServiceName(o.serviceName),
+				semconv.ServiceVersion(r.rt.AppVersion),
+			}, o.attrs...)...,
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		r.rt.Logger.Error().Err(err).Msg("otel resource init failed")
+		return r
+	}
+
+	traceExp, err := otlptracehttp.New(ctx)
+	if err != nil {
+		r.rt.Logger.Error().Err(err).Msg("otel trace exporter init failed")
+		return r
+	}
+
+	metricExp, err := otlpmetrichttp.New(ctx)
+	if err != nil {
+		r.rt.Logger.Error().Err(err).Msg("otel metric exporter init failed")
+		return r
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExp),
+		sdktrace.WithResource(res),
+	)
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExp)),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+
+	r.rt.Tracer = tp.Tracer(o.serviceName)
+	r.rt.Meter = mp.Meter(o.serviceName)
+
+	if r.srv != nil {
+		r.srv.Handler = otelhttp.NewHandler(r.rt.SrvMux, o.serviceName)
+	}
+
+	r.rt.OnShutdown(func(ctx context.Context) error {
+		if err := tp.Shutdown(ctx); err != nil {
+			return fmt.Errorf("shut down otel tracer provider: %w", err)
+		}
+
+		if err := mp.Shutdown(ctx); err != nil {
+			return fmt.Errorf("shut down otel meter provider: %w", err)
+		}
+
+		return nil
+	})
+
+	return r
+}
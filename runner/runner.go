@@ -1,19 +1,28 @@
 package runner
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/ashep/go-app/metrics"
-	"github.com/ashep/go-cfgloader"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/ashep/go-app/prommetrics"
+	"github.com/ashep/go-apprun/apprun"
+	"github.com/ashep/go-apprun/runner/cli"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -21,25 +30,129 @@ var (
 	appVer  = "" //nolint:gochecknoglobals // set externally
 )
 
-type Runtime struct {
+const (
+	defaultShutdownTimeout = 30 * time.Second
+	defaultShutdownDelay   = 0
+)
+
+// runtimeState holds the mutable registries shared by a Runtime and every
+// child derived from it via WithFields, so registering a health check or
+// shutdown hook on a child is visible to the runner driving Run.
+type runtimeState struct {
+	healthMu     sync.Mutex
+	healthChecks []healthCheck
+	ready        atomic.Bool
+
+	shutdownMu    sync.Mutex
+	shutdownHooks []func(context.Context) error
+
+	subsystemsMu sync.Mutex
+	subsystems   []registeredSubsystem
+}
+
+// configHookState holds the config-change hooks for a Runtime. It's
+// parameterized on CT (unlike runtimeState) because the hooks receive the
+// app's own config type, and is shared with Runtimes derived via WithFields.
+type configHookState[CT any] struct {
+	mu    sync.Mutex
+	hooks []func(CT) error
+}
+
+type Runtime[CT any] struct {
 	AppName    string
 	AppVersion string
 	Logger     zerolog.Logger
 	SrvMux     *http.ServeMux
+
+	// Tracer and Meter are populated by Runner.WithOpenTelemetry; until then
+	// they're nil, so app code should only use them if it called that method.
+	Tracer trace.Tracer
+	Meter  metric.Meter
+
+	state    *runtimeState
+	cfgHooks *configHookState[CT]
+}
+
+// WithFields returns a Runtime that logs with the given key-value pairs
+// added to every entry, e.g. rt.WithFields("component", "api"). It shares
+// the receiver's health checks, shutdown hooks, subsystem registry, and
+// config-change hooks, so it's meant for passing a component-scoped Runtime
+// down to a constructor, not for isolating state between components.
+func (rt *Runtime[CT]) WithFields(kv ...any) *Runtime[CT] {
+	child := *rt
+	child.Logger = rt.Logger.With().Fields(kv).Logger()
+
+	return &child
+}
+
+// OnShutdown registers a hook that runs during the teardown phase, after the
+// app's Run context is cancelled and before the HTTP server is shut down.
+// Hooks run in registration order and share the phased shutdown's deadline.
+func (rt *Runtime[CT]) OnShutdown(fn func(ctx context.Context) error) {
+	rt.state.shutdownMu.Lock()
+	defer rt.state.shutdownMu.Unlock()
+
+	rt.state.shutdownHooks = append(rt.state.shutdownHooks, fn)
+}
+
+func (rt *Runtime[CT]) runShutdownHooks(ctx context.Context) {
+	rt.state.shutdownMu.Lock()
+	hooks := make([]func(context.Context) error, len(rt.state.shutdownHooks))
+	copy(hooks, rt.state.shutdownHooks)
+	rt.state.shutdownMu.Unlock()
+
+	for _, fn := range hooks {
+		if err := fn(ctx); err != nil {
+			rt.Logger.Error().Err(err).Msg("shutdown hook failed")
+		}
+	}
+}
+
+// OnConfigChange registers a hook that runs whenever a config source reload
+// (see WithConfigSource) produces a new config. Hooks run in registration
+// order; a failing hook is logged and does not block the others.
+func (rt *Runtime[CT]) OnConfigChange(fn func(newCfg CT) error) {
+	rt.cfgHooks.mu.Lock()
+	defer rt.cfgHooks.mu.Unlock()
+
+	rt.cfgHooks.hooks = append(rt.cfgHooks.hooks, fn)
+}
+
+func (rt *Runtime[CT]) runConfigChangeHooks(newCfg CT) {
+	rt.cfgHooks.mu.Lock()
+	hooks := make([]func(CT) error, len(rt.cfgHooks.hooks))
+	copy(hooks, rt.cfgHooks.hooks)
+	rt.cfgHooks.mu.Unlock()
+
+	for _, fn := range hooks {
+		if err := fn(newCfg); err != nil {
+			rt.Logger.Error().Err(err).Msg("config change hook failed")
+		}
+	}
 }
 
 type Runnable interface {
 	Run(context.Context) error
 }
 
-type appFactory[RT Runnable, CT any] func(cfg CT, rt *Runtime) (RT, error)
+type appFactory[RT Runnable, CT any] func(cfg CT, rt *Runtime[CT]) (RT, error)
 
 type Runner[RT Runnable, CT any] struct {
-	cfg CT
-	fct appFactory[RT, CT]
-	lw  []io.Writer
-	srv *http.Server
-	rt  *Runtime
+	cfgMu   sync.Mutex
+	cfg     CT
+	baseCfg CT
+	fct     appFactory[RT, CT]
+	lw      []io.Writer
+	srv     *http.Server
+	rt      *Runtime[CT]
+
+	shutdownTimeout time.Duration
+	shutdownDelay   time.Duration
+
+	configSources []ConfigSource
+	overrides     []func(*CT)
+
+	cli *cli.Command
 }
 
 func New[RT Runnable, CT any](fct appFactory[RT, CT], cfg CT) *Runner[RT, CT] {
@@ -66,21 +179,74 @@ func New[RT Runnable, CT any](fct appFactory[RT, CT], cfg CT) *Runner[RT, CT] {
 		appVer = os.Getenv("APP_VERSION")
 	}
 
-	l := zerolog.New(zerolog.MultiLevelWriter(logWriters...)).Level(logLevel).
+	// The per-logger level is left at Debug; the effective level is enforced
+	// by zerolog's global level below, which reloadConfig can flip at runtime
+	// without racing every goroutine that reads Runtime.Logger.
+	l := zerolog.New(zerolog.MultiLevelWriter(logWriters...)).Level(zerolog.DebugLevel).
 		With().Str("app", appName).Str("app_v", appVer).Logger()
+	zerolog.SetGlobalLevel(logLevel)
+
+	rt := &Runtime[CT]{
+		AppName:    appName,
+		AppVersion: appVer,
+		Logger:     l,
+		state:      &runtimeState{},
+		cfgHooks:   &configHookState[CT]{},
+	}
+	rt.state.ready.Store(true)
 
 	return &Runner[RT, CT]{
-		cfg: cfg,
-		fct: fct,
-		lw:  logWriters,
-		rt: &Runtime{
-			AppName:    appName,
-			AppVersion: appVer,
-			Logger:     l,
-		},
+		cfg:             cfg,
+		baseCfg:         cfg,
+		fct:             fct,
+		lw:              logWriters,
+		rt:              rt,
+		shutdownTimeout: defaultShutdownTimeout,
+		shutdownDelay:   defaultShutdownDelay,
 	}
 }
 
+// WithConfigSource registers an additional config source, layered between
+// the built-in file loader and environment variables (custom sources apply
+// in registration order). Use this to plug in Consul, etcd, Vault, or an
+// HTTP source; if the source supports Watch, the runner reloads config and
+// calls any Runtime.OnConfigChange hooks whenever it fires.
+func (r *Runner[RT, CT]) WithConfigSource(src ConfigSource) *Runner[RT, CT] {
+	r.configSources = append(r.configSources, src)
+	return r
+}
+
+// WithConfigOverrides registers a function that sets explicit values after
+// every other source has loaded, so app code (e.g. a CLI flag) always wins
+// regardless of what files, custom sources, or env vars say. Overrides run
+// in registration order and are the last stage of the precedence chain.
+func (r *Runner[RT, CT]) WithConfigOverrides(fn func(*CT)) *Runner[RT, CT] {
+	r.overrides = append(r.overrides, fn)
+	return r
+}
+
+// WithShutdownTimeout bounds how long the runner waits for the app to return
+// from Run after its context is cancelled, and is reused as the single
+// deadline for stopping subsystems and running shutdown hooks. The HTTP
+// server's graceful Shutdown gets its own independent budget of the same
+// length, so a slow app/subsystem teardown can't eat into the server's grace
+// period. Past its deadline each stage proceeds anyway so the process
+// doesn't hang forever.
+func (r *Runner[RT, CT]) WithShutdownTimeout(d time.Duration) *Runner[RT, CT] {
+	r.shutdownTimeout = d
+	return r
+}
+
+// WithShutdownDelay sets how long the runner waits, after marking the
+// runtime not-ready, before cancelling the app's Run context. This gives
+// load balancers time to notice a failing /readyz and stop sending traffic
+// before in-flight work is interrupted. A second SIGINT/SIGTERM received
+// during this wait escalates to an immediate exit.
+func (r *Runner[RT, CT]) WithShutdownDelay(d time.Duration) *Runner[RT, CT] {
+	r.shutdownDelay = d
+	return r
+}
+
 func (r *Runner[RT, CT]) WithLogWriter(w io.Writer) *Runner[RT, CT] {
 	r.lw = append(r.lw, w)
 	return r
@@ -114,44 +280,145 @@ func (r *Runner[RT, CT]) WithMetricsHandler() *Runner[RT, CT] {
 		panic("http server is not set")
 	}
 
-	metrics.SetAppName(r.rt.AppName)
-	metrics.SetAppVersion(r.rt.AppVersion)
-
-	r.rt.SrvMux.Handle("/metrics", promhttp.Handler())
+	prommetrics.RegisterServer(r.rt.AppName, r.rt.AppVersion, r.rt.SrvMux)
 
 	return r
 }
 
-func (r *Runner[RT, CT]) Run() int {
-	for _, base := range []string{"config", appName} {
-		for _, ext := range []string{".yaml", ".json"} {
-			cfgPath := base + ext
-			err := cfgloader.LoadFromPath(cfgPath, &r.cfg, nil)
-			if err != nil && !errors.Is(err, os.ErrNotExist) {
-				r.rt.Logger.Error().Err(err).Str("path", cfgPath).Msg("config file load failed")
-				return 1
-			} else if err == nil {
-				r.rt.Logger.Debug().Str("path", cfgPath).Msg("config file loaded")
-			}
+// configSourceChain returns the full precedence chain: the built-in file
+// loader, then custom sources in registration order, then env vars, then any
+// explicit overrides (see WithConfigOverrides), which always win.
+func (r *Runner[RT, CT]) configSourceChain() []ConfigSource {
+	sources := make([]ConfigSource, 0, len(r.configSources)+len(r.overrides)+2)
+	sources = append(sources, fileConfigSource{appName: appName})
+	sources = append(sources, r.configSources...)
+	sources = append(sources, envConfigSource{})
+
+	for _, fn := range r.overrides {
+		sources = append(sources, overrideConfigSource[CT]{fn: fn})
+	}
+
+	return sources
+}
+
+// configKeyDiff returns the top-level JSON field names whose values differ
+// between before and after, so loadConfig/reloadConfig can log which keys a
+// given source actually set rather than just that it ran.
+func configKeyDiff(before, after any) []string {
+	bb, errB := json.Marshal(before)
+	ab, errA := json.Marshal(after)
+	if errB != nil || errA != nil {
+		return nil
+	}
+
+	var bm, am map[string]json.RawMessage
+	if json.Unmarshal(bb, &bm) != nil || json.Unmarshal(ab, &am) != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(am))
+	for k, av := range am {
+		if !bytes.Equal(bm[k], av) {
+			keys = append(keys, k)
 		}
 	}
+	sort.Strings(keys)
 
-	if cfgPath := os.Getenv("APP_CONFIG_PATH"); cfgPath != "" {
-		if err := cfgloader.LoadFromPath(cfgPath, &r.cfg, nil); err != nil {
-			r.rt.Logger.Error().Err(err).Str("path", cfgPath).Msg("config file load failed")
-			return 1
+	return keys
+}
+
+// loadConfig runs the config source chain into r.cfg. It's shared by run and
+// the "config print"/"config validate" CLI subcommands so they all see
+// exactly the config the app would start with.
+func (r *Runner[RT, CT]) loadConfig(ctx context.Context) error {
+	for _, src := range r.configSourceChain() {
+		before := r.cfg
+		if err := src.Load(ctx, &r.cfg); err != nil {
+			return err
 		}
 
-		r.rt.Logger.Debug().Str("path", cfgPath).Msg("config file loaded")
+		r.rt.Logger.Debug().
+			Str("source", fmt.Sprintf("%T", src)).
+			Strs("keys", configKeyDiff(before, r.cfg)).
+			Msg("config loaded from source")
 	}
 
-	if err := cfgloader.LoadFromEnv("APP", &r.cfg); err != nil {
-		r.rt.Logger.Error().Err(err).Msg("load config from env vars failed")
+	return nil
+}
+
+// configLogLeveler is implemented by app config types that want their log
+// level to follow a config field across reloads, since env vars (see
+// envConfigSource.Watch) can't change under a running process and so can't
+// serve that purpose after startup.
+type configLogLeveler interface {
+	LogLevel() zerolog.Level
+}
+
+// reloadConfig re-runs the config source chain from scratch (starting from
+// the config passed to New, not the currently-running r.cfg, so a removed
+// key doesn't linger) and notifies Runtime.OnConfigChange hooks; a reloaded
+// config that fails configValidator's Validate is discarded. It's
+// serialized with a mutex because it runs from each watched ConfigSource's
+// own goroutine, and two sources firing at once would otherwise race on
+// r.cfg. If the reloaded config implements configLogLeveler, its level is
+// applied via zerolog's global level rather than reassigning Runtime.Logger,
+// since that field is read without synchronization by every subsystem, app,
+// and HTTP-middleware goroutine.
+func (r *Runner[RT, CT]) reloadConfig(ctx context.Context) {
+	r.cfgMu.Lock()
+	defer r.cfgMu.Unlock()
+
+	newCfg := r.baseCfg
+
+	for _, src := range r.configSourceChain() {
+		before := newCfg
+		if err := src.Load(ctx, &newCfg); err != nil {
+			r.rt.Logger.Error().Err(err).Msg("config reload failed")
+			return
+		}
+
+		r.rt.Logger.Debug().
+			Str("source", fmt.Sprintf("%T", src)).
+			Strs("keys", configKeyDiff(before, newCfg)).
+			Msg("config reloaded from source")
+	}
+
+	if v, ok := any(newCfg).(configValidator); ok {
+		if err := v.Validate(); err != nil {
+			r.rt.Logger.Error().Err(err).Msg("reloaded config is invalid, keeping the current config")
+			return
+		}
+	}
+
+	if lvl, ok := any(newCfg).(configLogLeveler); ok {
+		zerolog.SetGlobalLevel(lvl.LogLevel())
+	}
+
+	r.cfg = newCfg
+	r.rt.Logger.Info().Msg("config reloaded")
+	r.rt.runConfigChangeHooks(newCfg)
+}
+
+// Run either runs the attached CLI's subcommand (see WithCLI) or, if none is
+// attached, loads config and starts the app directly. Config is loaded only
+// by starting the app and by the "config" subcommands.
+func (r *Runner[RT, CT]) Run() int {
+	if r.cli != nil {
+		return r.runCLI(os.Args[1:])
+	}
+
+	return r.run()
+}
+
+func (r *Runner[RT, CT]) run() int {
+	if err := r.loadConfig(context.Background()); err != nil {
+		r.rt.Logger.Error().Err(err).Msg("config load failed")
 		return 1
 	}
 
-	sig := make(chan os.Signal, 1)
+	sig := make(chan os.Signal, 2)
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
 
 	app, err := r.fct(r.cfg, r.rt)
 	if err != nil {
@@ -161,10 +428,30 @@ func (r *Runner[RT, CT]) Run() int {
 
 	ctx, ctxC := context.WithCancel(context.Background())
 	defer ctxC()
+	ctx = apprun.WithLogger(ctx, r.rt.Logger)
 
 	go func() {
 		s := <-sig
-		r.rt.Logger.Info().Str("signal", s.String()).Msg("signal received")
+		r.rt.Logger.Info().Str("signal", s.String()).Msg("signal received, starting graceful shutdown")
+
+		// (1) mark not-ready so load balancers stop sending new traffic.
+		r.rt.SetReady(false)
+
+		// A second SIGINT/SIGTERM at any point from here through teardown
+		// escalates to an immediate exit, so a hung shutdown can still be
+		// aborted regardless of ShutdownDelay.
+		go func() {
+			s := <-sig
+			r.rt.Logger.Warn().Str("signal", s.String()).Msg("second signal received, exiting immediately")
+			os.Exit(1)
+		}()
+
+		// (2) give load balancers a chance to notice before we cancel ctx.
+		if r.shutdownDelay > 0 {
+			time.Sleep(r.shutdownDelay)
+		}
+
+		// (3) cancel the app's context.
 		ctxC()
 	}()
 
@@ -179,18 +466,166 @@ func (r *Runner[RT, CT]) Run() int {
 		}()
 	}
 
-	if err := app.Run(ctx); err != nil {
-		r.rt.Logger.Error().Err(err).Msg("app run failed")
-		return 1
+	eg, egCtx := errgroup.WithContext(ctx)
+
+	r.rt.state.subsystemsMu.Lock()
+	subs := make([]registeredSubsystem, len(r.rt.state.subsystems))
+	copy(subs, r.rt.state.subsystems)
+	r.rt.state.subsystemsMu.Unlock()
+
+	for _, s := range subs {
+		s := s
+
+		eg.Go(func() error {
+			l := r.rt.Logger.With().Str("subsystem", s.name).Logger()
+
+			l.Info().Msg("subsystem is starting")
+			if err := s.sub.Start(egCtx); err != nil {
+				l.Error().Err(err).Msg("subsystem failed")
+				return err
+			}
+
+			l.Info().Msg("subsystem stopped")
+			return nil
+		})
+	}
+
+	for _, src := range r.configSources {
+		src := src
+
+		ch, err := src.Watch(egCtx)
+		if err != nil {
+			r.rt.Logger.Error().Err(err).Str("source", fmt.Sprintf("%T", src)).Msg("config source watch failed to start")
+			continue
+		}
+
+		if ch == nil {
+			continue
+		}
+
+		eg.Go(func() error {
+			for {
+				select {
+				case <-egCtx.Done():
+					return nil
+				case _, ok := <-ch:
+					if !ok {
+						return nil
+					}
+
+					r.reloadConfig(egCtx)
+				}
+			}
+		})
+	}
+
+	appDone := make(chan error, 1)
+	eg.Go(func() error {
+		err := app.Run(egCtx)
+		appDone <- err
+		return err
+	})
+
+	var runErr error
+
+	appReturned := false
+	select {
+	case runErr = <-appDone:
+		appReturned = true
+	case <-ctx.Done():
+	}
+
+	// shutdownCtx is the single ShutdownTimeout-wide budget for everything
+	// from here on: waiting for the app to return after ctx is cancelled,
+	// stopping subsystems, running shutdown hooks, and eg.Wait below. It's
+	// created once, when shutdown begins and before any of those stages, so
+	// they share one deadline instead of each getting a full ShutdownTimeout
+	// back-to-back.
+	shutdownCtx, shutdownCtxC := context.WithTimeout(context.Background(), r.shutdownTimeout)
+	defer shutdownCtxC()
+
+	if !appReturned {
+		select {
+		case runErr = <-appDone:
+		case <-shutdownCtx.Done():
+			r.rt.Logger.Warn().Dur("timeout", r.shutdownTimeout).
+				Msg("app did not return within shutdown timeout, proceeding with teardown anyway")
+		}
+	}
+
+	// Cancel ctx (and, through errgroup, egCtx) unconditionally here: app.Run
+	// may have returned on its own without a signal ever arriving, in which
+	// case nothing else would cancel it, and every subsystem's Start(egCtx)
+	// would block forever in the eg.Wait() below even after stopSubsystems
+	// calls Stop.
+	ctxC()
+
+	// Defensive: cover the case where app.Run returned on its own, without a signal.
+	r.rt.SetReady(false)
+
+	// (4) stop subsystems and run shutdown hooks, bounded by shutdownCtx too:
+	// stopSubsystems' wg.Wait() and a shutdown hook call block unconditionally
+	// on their own, so a Subsystem.Stop or hook that ignores ctx cancellation
+	// must not be able to hang run() forever either.
+	teardownDone := make(chan struct{})
+	go func() {
+		r.rt.stopSubsystems(shutdownCtx)
+		r.rt.runShutdownHooks(shutdownCtx)
+		close(teardownDone)
+	}()
+
+	select {
+	case <-teardownDone:
+	case <-shutdownCtx.Done():
+		r.rt.Logger.Warn().Msg("subsystem stop / shutdown hooks did not return within shutdown timeout, proceeding anyway")
+	}
+
+	// Bound this on shutdownCtx too: an app or subsystem that ignores ctx
+	// cancellation must not be able to hang run() forever just because it's
+	// still a goroutine in eg.
+	egWaitDone := make(chan error, 1)
+	go func() { egWaitDone <- eg.Wait() }()
+
+	select {
+	case err := <-egWaitDone:
+		if err != nil && runErr == nil {
+			runErr = err
+		}
+	case <-shutdownCtx.Done():
+		r.rt.Logger.Warn().Msg("app/subsystems did not stop within shutdown timeout, proceeding with teardown anyway")
+
+		// eg.Wait hasn't actually returned; don't lose a genuine failure that
+		// arrives after we've stopped waiting for it, even though it's too
+		// late to affect runErr/the exit code.
+		go func() {
+			if err := <-egWaitDone; err != nil {
+				r.rt.Logger.Error().Err(err).Msg("app/subsystem failed after shutdown timeout had already elapsed")
+			}
+		}()
 	}
 
 	if r.srv != nil {
 		r.rt.Logger.Info().Msg("http server is shutting down")
-		if err := r.srv.Shutdown(context.Background()); err != nil {
-			r.rt.Logger.Error().Err(err).Msg("http server shutdown failed")
+
+		// (5) its own ShutdownTimeout-wide budget, independent of shutdownCtx
+		// above, so a slow app/subsystem teardown can't leave the server with
+		// no real grace period before it's force-closed.
+		srvShutdownCtx, srvShutdownCtxC := context.WithTimeout(context.Background(), r.shutdownTimeout)
+		defer srvShutdownCtxC()
+
+		if err := r.srv.Shutdown(srvShutdownCtx); err != nil {
+			r.rt.Logger.Warn().Err(err).Msg("http server graceful shutdown failed, forcing close")
+			if err := r.srv.Close(); err != nil {
+				r.rt.Logger.Error().Err(err).Msg("http server force close failed")
+			}
 		}
 	}
 
+	if runErr != nil {
+		r.rt.Logger.Error().Err(runErr).Msg("app run failed")
+		return 1
+	}
+
 	return 0
 }
 
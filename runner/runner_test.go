@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type blockingApp struct{}
+
+func (blockingApp) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+type recordingSubsystem struct {
+	mu      *sync.Mutex
+	stopped *bool
+}
+
+func (recordingSubsystem) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (s recordingSubsystem) Stop(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.stopped = true
+
+	return nil
+}
+
+// TestRunShutdownUnblocksOnSignal is a regression test for a deadlock where
+// a signal-triggered shutdown stopped subsystems but never cancelled egCtx,
+// so any subsystem blocking in Start(egCtx) (per the Subsystem contract) kept
+// eg.Wait() from ever returning.
+func TestRunShutdownUnblocksOnSignal(t *testing.T) {
+	var mu sync.Mutex
+	stopped := false
+
+	r := New[*blockingApp, testConfig](func(testConfig, *Runtime[testConfig]) (*blockingApp, error) {
+		return &blockingApp{}, nil
+	}, testConfig{})
+	r.rt.Register("sub", recordingSubsystem{mu: &mu, stopped: &stopped})
+	r.WithShutdownTimeout(2 * time.Second)
+
+	done := make(chan int, 1)
+	go func() { done <- r.run() }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+
+	select {
+	case code := <-done:
+		if code != 0 {
+			t.Fatalf("want exit code 0, got %d", code)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("run() did not return after SIGTERM; a subsystem Start is likely still blocked on an uncancelled egCtx")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !stopped {
+		t.Fatal("want the subsystem's Stop to have run during shutdown")
+	}
+}
+
+type stubbornSubsystem struct{}
+
+func (stubbornSubsystem) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (stubbornSubsystem) Stop(context.Context) error {
+	select {} //nolint:staticcheck // deliberately ignores ctx, for TestRunShutdownTimesOutWhenSubsystemStopIgnoresContext
+}
+
+// TestRunShutdownTimesOutWhenSubsystemStopIgnoresContext is a regression test
+// for stopSubsystems' wg.Wait() blocking run() forever when a registered
+// Subsystem's Stop ignores ctx cancellation: ShutdownTimeout must bound it
+// just like it bounds waiting for the app itself.
+func TestRunShutdownTimesOutWhenSubsystemStopIgnoresContext(t *testing.T) {
+	r := New[*blockingApp, testConfig](func(testConfig, *Runtime[testConfig]) (*blockingApp, error) {
+		return &blockingApp{}, nil
+	}, testConfig{})
+	r.rt.Register("stubborn", stubbornSubsystem{})
+	r.WithShutdownTimeout(200 * time.Millisecond)
+
+	done := make(chan int, 1)
+	go func() { done <- r.run() }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("run() did not return within roughly ShutdownTimeout of a subsystem whose Stop never returns")
+	}
+}
+
+type stubbornApp struct{}
+
+func (stubbornApp) Run(context.Context) error {
+	select {} //nolint:staticcheck // deliberately ignores ctx cancellation, for TestRunShutdownTimesOutWhenAppIgnoresContext
+}
+
+// TestRunShutdownTimesOutWhenAppIgnoresContext is a regression test for
+// run() hanging forever on eg.Wait() when app.Run ignores ctx cancellation:
+// ShutdownTimeout must bound the wait on its own, without relying on a
+// second signal to force an exit.
+func TestRunShutdownTimesOutWhenAppIgnoresContext(t *testing.T) {
+	r := New[*stubbornApp, testConfig](func(testConfig, *Runtime[testConfig]) (*stubbornApp, error) {
+		return &stubbornApp{}, nil
+	}, testConfig{})
+	r.WithShutdownTimeout(200 * time.Millisecond)
+
+	done := make(chan int, 1)
+	go func() { done <- r.run() }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("run() did not return within roughly ShutdownTimeout of an app that never returns from Run")
+	}
+}
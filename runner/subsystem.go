@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"sync"
+)
+
+// Subsystem is a long-running component started and stopped alongside the
+// app's own Run method, such as a gRPC server, a message-queue consumer, or
+// a background scheduler. Start should block until ctx is cancelled (or the
+// subsystem fails) and Stop should release any resources it holds.
+type Subsystem interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type registeredSubsystem struct {
+	name string
+	sub  Subsystem
+}
+
+// Register adds a subsystem that the runner starts concurrently with the
+// app and stops during teardown. If any registered subsystem's Start
+// returns an error, the app's context is cancelled.
+func (rt *Runtime[CT]) Register(name string, s Subsystem) {
+	rt.state.subsystemsMu.Lock()
+	defer rt.state.subsystemsMu.Unlock()
+
+	rt.state.subsystems = append(rt.state.subsystems, registeredSubsystem{name: name, sub: s})
+}
+
+func (rt *Runtime[CT]) stopSubsystems(ctx context.Context) {
+	rt.state.subsystemsMu.Lock()
+	subs := make([]registeredSubsystem, len(rt.state.subsystems))
+	copy(subs, rt.state.subsystems)
+	rt.state.subsystemsMu.Unlock()
+
+	var wg sync.WaitGroup
+
+	for _, s := range subs {
+		wg.Add(1)
+
+		go func(s registeredSubsystem) {
+			defer wg.Done()
+
+			l := rt.Logger.With().Str("subsystem", s.name).Logger()
+
+			l.Info().Msg("subsystem is stopping")
+			if err := s.sub.Stop(ctx); err != nil {
+				l.Error().Err(err).Msg("subsystem stop failed")
+			}
+		}(s)
+	}
+
+	wg.Wait()
+}